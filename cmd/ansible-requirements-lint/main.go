@@ -0,0 +1,123 @@
+// Command ansible-requirements-lint resolves the versions available
+// for every role and collection declared in a requirements.yml file,
+// and reports any that could not be found.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/atosatto/ansible-requirements-lint/provider"
+	"github.com/atosatto/ansible-requirements-lint/requirements"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "ansible-requirements-lint:", err)
+		os.Exit(1)
+	}
+}
+
+// config holds the parsed command-line flags.
+type config struct {
+	file        string
+	concurrency int
+	noCache     bool
+	cacheTTL    time.Duration
+	galaxyURL   string
+}
+
+// parseFlags parses args into a config, independently of run so the
+// flag wiring (names, defaults) can be tested without touching the
+// network or the filesystem.
+func parseFlags(args []string) (config, error) {
+	var cfg config
+	fs := flag.NewFlagSet("ansible-requirements-lint", flag.ExitOnError)
+	fs.StringVar(&cfg.file, "file", "requirements.yml", "path to the requirements.yml file to lint")
+	fs.IntVar(&cfg.concurrency, "concurrency", provider.DefaultConcurrency, "maximum number of Galaxy/git requests to run in parallel")
+	fs.BoolVar(&cfg.noCache, "no-cache", false, "bypass the on-disk Galaxy response cache")
+	fs.DurationVar(&cfg.cacheTTL, "cache-ttl", provider.DefaultCacheTTL, "how long a cached Galaxy response is served without revalidation")
+	fs.StringVar(&cfg.galaxyURL, "galaxy-url", provider.DefaultAnsibleGalaxyURL, "base URL of the Ansible Galaxy server to query")
+	if err := fs.Parse(args); err != nil {
+		return config{}, err
+	}
+	return cfg, nil
+}
+
+func run(args []string) error {
+	cfg, err := parseFlags(args)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(cfg.file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reqs, err := requirements.Parse(f)
+	if err != nil {
+		return fmt.Errorf("unable to parse %s: %w", cfg.file, err)
+	}
+
+	cacheDir, err := provider.CacheDir()
+	if err != nil {
+		return err
+	}
+
+	galaxy := provider.NewAnsibleGalaxy(cfg.galaxyURL, provider.AuthConfig{}).
+		WithRetry(3).
+		WithCache(cacheDir, cfg.cacheTTL, cfg.noCache)
+	dispatch := provider.NewDispatch(galaxy)
+
+	results, err := provider.Resolve(context.Background(), dispatch, reqs, provider.ResolveOptions{Concurrency: cfg.concurrency})
+	if err != nil {
+		return err
+	}
+
+	return report(results)
+}
+
+// report prints a one-line summary per requirement and returns an
+// error if any of them could not be resolved.
+func report(results []provider.Resolution) error {
+	var failed int
+	for _, r := range results {
+		name, err := describe(r)
+		if err != nil {
+			return err
+		}
+		if r.Err != nil {
+			failed++
+			fmt.Printf("FAIL  %s: %v\n", name, r.Err)
+			continue
+		}
+		fmt.Printf("OK    %s: %d version(s), latest %s\n", name, len(r.Versions), latest(r.Versions))
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d requirement(s) could not be resolved", failed)
+	}
+	return nil
+}
+
+func describe(r provider.Resolution) (string, error) {
+	switch {
+	case r.Role != nil:
+		return "role " + r.Role.Name, nil
+	case r.Collection != nil:
+		return "collection " + r.Collection.Name, nil
+	default:
+		return "", fmt.Errorf("resolution has neither a role nor a collection")
+	}
+}
+
+func latest(versions []string) string {
+	if len(versions) == 0 {
+		return "none"
+	}
+	return versions[len(versions)-1]
+}
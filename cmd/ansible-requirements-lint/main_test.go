@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/atosatto/ansible-requirements-lint/provider"
+)
+
+func TestParseFlagsDefaults(t *testing.T) {
+	cfg, err := parseFlags(nil)
+	if err != nil {
+		t.Fatalf("parseFlags: %v", err)
+	}
+	if cfg.file != "requirements.yml" {
+		t.Errorf("file = %q, want %q", cfg.file, "requirements.yml")
+	}
+	if cfg.concurrency != provider.DefaultConcurrency {
+		t.Errorf("concurrency = %d, want %d", cfg.concurrency, provider.DefaultConcurrency)
+	}
+	if cfg.noCache {
+		t.Error("noCache = true, want false")
+	}
+	if cfg.cacheTTL != provider.DefaultCacheTTL {
+		t.Errorf("cacheTTL = %v, want %v", cfg.cacheTTL, provider.DefaultCacheTTL)
+	}
+	if cfg.galaxyURL != provider.DefaultAnsibleGalaxyURL {
+		t.Errorf("galaxyURL = %q, want %q", cfg.galaxyURL, provider.DefaultAnsibleGalaxyURL)
+	}
+}
+
+func TestParseFlagsOverrides(t *testing.T) {
+	cfg, err := parseFlags([]string{
+		"-file", "custom.yml",
+		"-concurrency", "2",
+		"-no-cache",
+		"-cache-ttl", "5m",
+		"-galaxy-url", "https://galaxy.internal.example.com",
+	})
+	if err != nil {
+		t.Fatalf("parseFlags: %v", err)
+	}
+	if cfg.file != "custom.yml" {
+		t.Errorf("file = %q, want %q", cfg.file, "custom.yml")
+	}
+	if cfg.concurrency != 2 {
+		t.Errorf("concurrency = %d, want 2", cfg.concurrency)
+	}
+	if !cfg.noCache {
+		t.Error("noCache = false, want true")
+	}
+	if cfg.cacheTTL.String() != "5m0s" {
+		t.Errorf("cacheTTL = %v, want 5m0s", cfg.cacheTTL)
+	}
+	if cfg.galaxyURL != "https://galaxy.internal.example.com" {
+		t.Errorf("galaxyURL = %q, want %q", cfg.galaxyURL, "https://galaxy.internal.example.com")
+	}
+}
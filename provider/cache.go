@@ -0,0 +1,195 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultCacheTTL is how long a cached Galaxy response is served
+// without revalidation before CachingTransport asks the upstream
+// whether it has changed.
+const DefaultCacheTTL = 1 * time.Hour
+
+// CacheDir returns the directory ansible-requirements-lint caches
+// Galaxy responses under, honoring $XDG_CACHE_HOME.
+func CacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "ansible-requirements-lint"), nil
+}
+
+// cacheEntry is what CachingTransport persists to disk for a single
+// URL.
+type cacheEntry struct {
+	ETag         string
+	LastModified string
+	StoredAt     time.Time
+	Response     []byte // a serialized net/http/httputil DumpResponse
+}
+
+// CachingTransport is an http.RoundTripper that caches GET responses
+// on disk, keyed by URL, and revalidates them with the upstream using
+// If-None-Match/If-Modified-Since once they are older than TTL.
+type CachingTransport struct {
+	// Base is the underlying transport used for actual network
+	// requests. http.DefaultTransport is used if nil.
+	Base http.RoundTripper
+	// Dir is the directory cache entries are stored under.
+	Dir string
+	// TTL is how long an entry is served without revalidation.
+	TTL time.Duration
+	// Disabled bypasses the cache entirely. It backs the CLI's
+	// `--no-cache` flag, letting callers force a live lookup without a
+	// second non-caching code path.
+	Disabled bool
+}
+
+// NewCachingTransport creates a CachingTransport that stores entries
+// under dir, wrapping base (http.DefaultTransport if nil).
+func NewCachingTransport(base http.RoundTripper, dir string, ttl time.Duration) *CachingTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &CachingTransport{Base: base, Dir: dir, TTL: ttl}
+}
+
+// WithCache returns a copy of g whose requests are cached on disk
+// under dir, revalidated after ttl. Passing disabled bypasses the
+// cache entirely, for callers wiring up a `--no-cache` flag.
+func (g AnsibleGalaxy) WithCache(dir string, ttl time.Duration, disabled bool) AnsibleGalaxy {
+	transport := NewCachingTransport(g.baseTransport(), dir, ttl)
+	transport.Disabled = disabled
+	g.transport = transport
+	return g
+}
+
+func (t *CachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.Disabled || req.Method != http.MethodGet {
+		return t.base().RoundTrip(req)
+	}
+
+	path := t.entryPath(req.URL.String())
+
+	entry, err := readCacheEntry(path)
+	if err == nil {
+		if time.Since(entry.StoredAt) < t.TTL {
+			if resp, err := entry.response(req); err == nil {
+				return resp, nil
+			}
+		}
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := t.base().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry != nil && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		entry.StoredAt = time.Now()
+		_ = writeCacheEntry(path, *entry)
+		return entry.response(req)
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		dump, err := httputil.DumpResponse(resp, true)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		newEntry := cacheEntry{
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			StoredAt:     time.Now(),
+			Response:     dump,
+		}
+		_ = writeCacheEntry(path, newEntry)
+		return newEntry.response(req)
+	}
+
+	return resp, nil
+}
+
+func (t *CachingTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func (t *CachingTransport) entryPath(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(t.Dir, hex.EncodeToString(sum[:])+".cache")
+}
+
+func (e cacheEntry) response(req *http.Request) (*http.Response, error) {
+	return http.ReadResponse(bufio.NewReader(bytes.NewReader(e.Response)), req)
+}
+
+func readCacheEntry(path string) (*cacheEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entry cacheEntry
+	if err := gob.NewDecoder(f).Decode(&entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// writeCacheEntry writes entry to path atomically: it is encoded to a
+// temporary file in the same directory and then renamed into place, so
+// concurrent lint invocations never observe (or corrupt) a partially
+// written entry.
+func writeCacheEntry(path string, entry cacheEntry) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	if err := gob.NewEncoder(tmp).Encode(entry); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("unable to write cache entry: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package provider
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubTransport serves canned responses and records the requests it
+// receives, so tests can assert on conditional headers without a
+// network round-trip.
+type stubTransport struct {
+	responses []*http.Response
+	requests  []*http.Request
+}
+
+func (s *stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.requests = append(s.requests, req)
+	resp := s.responses[len(s.requests)-1]
+	resp.Request = req
+	return resp, nil
+}
+
+func newResponse(status int, body string, headers map[string]string) *http.Response {
+	resp := &http.Response{
+		StatusCode: status,
+		Status:     http.StatusText(status),
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+	for k, v := range headers {
+		resp.Header.Set(k, v)
+	}
+	return resp
+}
+
+func TestCachingTransportServesFromCacheWithinTTL(t *testing.T) {
+	dir := t.TempDir()
+	stub := &stubTransport{responses: []*http.Response{
+		newResponse(200, `{"count":1}`, map[string]string{"ETag": `"v1"`}),
+	}}
+	ct := NewCachingTransport(stub, dir, time.Hour)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://galaxy.example.com/api/v1/roles/", nil)
+
+	resp1, err := ct.RoundTrip(req.Clone(req.Context()))
+	if err != nil {
+		t.Fatalf("first RoundTrip: %v", err)
+	}
+	body1, _ := io.ReadAll(resp1.Body)
+	if string(body1) != `{"count":1}` {
+		t.Fatalf("unexpected body: %s", body1)
+	}
+
+	resp2, err := ct.RoundTrip(req.Clone(req.Context()))
+	if err != nil {
+		t.Fatalf("second RoundTrip: %v", err)
+	}
+	body2, _ := io.ReadAll(resp2.Body)
+	if string(body2) != `{"count":1}` {
+		t.Fatalf("unexpected cached body: %s", body2)
+	}
+
+	if len(stub.requests) != 1 {
+		t.Fatalf("expected the cached response to skip the upstream, got %d upstream requests", len(stub.requests))
+	}
+}
+
+func TestCachingTransportRevalidatesAfterTTL(t *testing.T) {
+	dir := t.TempDir()
+	stub := &stubTransport{responses: []*http.Response{
+		newResponse(200, `{"count":1}`, map[string]string{"ETag": `"v1"`}),
+		newResponse(http.StatusNotModified, "", nil),
+	}}
+	ct := NewCachingTransport(stub, dir, 0)
+	ct.TTL = -1 * time.Second // force every request to revalidate
+
+	req, _ := http.NewRequest(http.MethodGet, "https://galaxy.example.com/api/v1/roles/", nil)
+
+	if _, err := ct.RoundTrip(req.Clone(req.Context())); err != nil {
+		t.Fatalf("first RoundTrip: %v", err)
+	}
+	resp, err := ct.RoundTrip(req.Clone(req.Context()))
+	if err != nil {
+		t.Fatalf("second RoundTrip: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != `{"count":1}` {
+		t.Fatalf("expected the cached body to be served on a 304, got: %s", body)
+	}
+
+	if len(stub.requests) != 2 {
+		t.Fatalf("expected 2 upstream requests, got %d", len(stub.requests))
+	}
+	if got := stub.requests[1].Header.Get("If-None-Match"); got != `"v1"` {
+		t.Fatalf("expected revalidation to send If-None-Match, got %q", got)
+	}
+}
+
+func TestCachingTransportDisabledBypassesCache(t *testing.T) {
+	dir := t.TempDir()
+	stub := &stubTransport{responses: []*http.Response{
+		newResponse(200, `{"count":1}`, nil),
+		newResponse(200, `{"count":2}`, nil),
+	}}
+	ct := NewCachingTransport(stub, dir, time.Hour)
+	ct.Disabled = true
+
+	req, _ := http.NewRequest(http.MethodGet, "https://galaxy.example.com/api/v1/roles/", nil)
+
+	resp1, _ := ct.RoundTrip(req.Clone(req.Context()))
+	body1, _ := io.ReadAll(resp1.Body)
+	resp2, _ := ct.RoundTrip(req.Clone(req.Context()))
+	body2, _ := io.ReadAll(resp2.Body)
+
+	if string(body1) == string(body2) {
+		t.Fatalf("expected --no-cache to hit the upstream every time, got the same body twice: %s", body1)
+	}
+	if len(stub.requests) != 2 {
+		t.Fatalf("expected 2 upstream requests, got %d", len(stub.requests))
+	}
+}
+
+func TestWriteCacheEntryIsAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := dir + "/entry.cache"
+
+	if err := writeCacheEntry(path, cacheEntry{ETag: `"v1"`, StoredAt: time.Now()}); err != nil {
+		t.Fatalf("writeCacheEntry: %v", err)
+	}
+
+	entry, err := readCacheEntry(path)
+	if err != nil {
+		t.Fatalf("readCacheEntry: %v", err)
+	}
+	if entry.ETag != `"v1"` {
+		t.Fatalf("unexpected ETag: %q", entry.ETag)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("os.ReadDir: %v", err)
+	}
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".tmp-") {
+			t.Fatalf("temp file left behind: %s", e.Name())
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/atosatto/ansible-requirements-lint/requirements"
+)
+
+type collectionVersionsPage struct {
+	Data  []struct {
+		Version string `json:"version"`
+	} `json:"data"`
+	Links struct {
+		Next string `json:"next"`
+	} `json:"links"`
+}
+
+func writeCollectionPage(w http.ResponseWriter, versions []string, next string) {
+	var page collectionVersionsPage
+	for _, v := range versions {
+		page.Data = append(page.Data, struct {
+			Version string `json:"version"`
+		}{Version: v})
+	}
+	page.Links.Next = next
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(page)
+}
+
+func TestVersionsForCollectionFollowsPagination(t *testing.T) {
+	var requestedPaths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPaths = append(requestedPaths, r.URL.Path+"?"+r.URL.RawQuery)
+		switch len(requestedPaths) {
+		case 1:
+			if r.URL.Path != "/api/v2/collections/community/general/versions/" {
+				t.Fatalf("unexpected first page path: %s", r.URL.Path)
+			}
+			writeCollectionPage(w, []string{"1.0.0", "1.2.0"}, "/api/v2/collections/community/general/versions/?page=2")
+		case 2:
+			writeCollectionPage(w, []string{"1.10.0"}, "")
+		default:
+			t.Fatalf("unexpected extra request: %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	g := NewAnsibleGalaxy(srv.URL, AuthConfig{})
+	versions, err := g.VersionsForCollection(context.Background(), requirements.Collection{Name: "community.general"})
+	if err != nil {
+		t.Fatalf("VersionsForCollection: %v", err)
+	}
+	if len(requestedPaths) != 2 {
+		t.Fatalf("expected the paginator to follow the next link, made %d requests: %v", len(requestedPaths), requestedPaths)
+	}
+
+	want := []string{"1.0.0", "1.2.0", "1.10.0"}
+	if fmt.Sprint(versions) != fmt.Sprint(want) {
+		t.Fatalf("expected numerically sorted merged versions %v, got %v", want, versions)
+	}
+}
+
+func TestVersionsForCollectionNoResultsIsError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeCollectionPage(w, nil, "")
+	}))
+	defer srv.Close()
+
+	g := NewAnsibleGalaxy(srv.URL, AuthConfig{})
+	_, err := g.VersionsForCollection(context.Background(), requirements.Collection{Name: "community.general"})
+	if err == nil {
+		t.Fatal("expected an error when no versions are returned")
+	}
+}
+
+func TestVersionsForCollectionRejectsMalformedName(t *testing.T) {
+	g := NewAnsibleGalaxy("https://galaxy.example.com", AuthConfig{})
+	_, err := g.VersionsForCollection(context.Background(), requirements.Collection{Name: "not-a-fqcn"})
+	if err == nil {
+		t.Fatal("expected an error for a collection name without a namespace")
+	}
+}
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UpstreamAuthConfig is the on-disk representation of an upstream's
+// AuthConfig.
+type UpstreamAuthConfig struct {
+	Type     string `yaml:"type" json:"type"` // "none" (default), "token" or "basic"
+	Token    string `yaml:"token" json:"token"`
+	Username string `yaml:"username" json:"username"`
+	Password string `yaml:"password" json:"password"`
+}
+
+// UpstreamConfig describes a single Galaxy-compatible server in a
+// registry configuration file.
+type UpstreamConfig struct {
+	Name               string             `yaml:"name" json:"name"`
+	BaseURL            string             `yaml:"baseUrl" json:"baseUrl"`
+	Auth               UpstreamAuthConfig `yaml:"auth" json:"auth"`
+	InsecureSkipVerify bool               `yaml:"insecureSkipVerify" json:"insecureSkipVerify"`
+}
+
+// RegistryConfig is the top-level shape of a registry configuration
+// file: an ordered list of upstreams, highest priority first.
+type RegistryConfig struct {
+	Upstreams []UpstreamConfig `yaml:"upstreams" json:"upstreams"`
+}
+
+// LoadRegistryConfig reads a registry configuration file. Both YAML
+// and JSON are accepted, since JSON is a subset of YAML.
+func LoadRegistryConfig(path string) (RegistryConfig, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return RegistryConfig{}, err
+	}
+	defer f.Close()
+	return parseRegistryConfig(f)
+}
+
+func parseRegistryConfig(r io.Reader) (RegistryConfig, error) {
+	var cfg RegistryConfig
+	if err := yaml.NewDecoder(r).Decode(&cfg); err != nil {
+		return RegistryConfig{}, err
+	}
+	return cfg, nil
+}
+
+// NewRegistryFromConfig builds a Registry out of a RegistryConfig, in the order
+// its upstreams are declared.
+func NewRegistryFromConfig(cfg RegistryConfig) (*Registry, error) {
+	reg := NewRegistry()
+	for _, u := range cfg.Upstreams {
+		auth, err := authConfigFromUpstream(u)
+		if err != nil {
+			return nil, fmt.Errorf("upstream %q: %w", u.Name, err)
+		}
+
+		galaxy := NewAnsibleGalaxy(u.BaseURL, auth)
+		if u.InsecureSkipVerify {
+			galaxy = galaxy.WithTLSConfig(insecureTLSConfig())
+		}
+		reg.Add(u.Name, galaxy)
+	}
+	return reg, nil
+}
+
+func insecureTLSConfig() *tls.Config {
+	return &tls.Config{InsecureSkipVerify: true}
+}
+
+func authConfigFromUpstream(u UpstreamConfig) (AuthConfig, error) {
+	switch u.Auth.Type {
+	case "", "none":
+		return AuthConfig{}, nil
+	case "token":
+		return AuthConfig{Type: AuthToken, Token: u.Auth.Token}, nil
+	case "basic":
+		return AuthConfig{Type: AuthBasic, Username: u.Auth.Username, Password: u.Auth.Password}, nil
+	default:
+		return AuthConfig{}, fmt.Errorf("unknown auth type %q", u.Auth.Type)
+	}
+}
@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAuthConfigFromUpstream(t *testing.T) {
+	cases := []struct {
+		name string
+		in   UpstreamConfig
+		want AuthConfig
+	}{
+		{name: "default is none", in: UpstreamConfig{}, want: AuthConfig{}},
+		{
+			name: "token",
+			in:   UpstreamConfig{Auth: UpstreamAuthConfig{Type: "token", Token: "s3cr3t"}},
+			want: AuthConfig{Type: AuthToken, Token: "s3cr3t"},
+		},
+		{
+			name: "basic",
+			in:   UpstreamConfig{Auth: UpstreamAuthConfig{Type: "basic", Username: "u", Password: "p"}},
+			want: AuthConfig{Type: AuthBasic, Username: "u", Password: "p"},
+		},
+	}
+
+	for _, c := range cases {
+		got, err := authConfigFromUpstream(c.in)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("%s: got %+v, want %+v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestAuthConfigFromUpstreamUnknownType(t *testing.T) {
+	_, err := authConfigFromUpstream(UpstreamConfig{Auth: UpstreamAuthConfig{Type: "kerberos"}})
+	if err == nil {
+		t.Fatal("expected an error for an unknown auth type")
+	}
+}
+
+func TestParseRegistryConfig(t *testing.T) {
+	doc := `
+upstreams:
+  - name: internal
+    baseUrl: https://galaxy.internal.example.com
+    auth:
+      type: token
+      token: s3cr3t
+    insecureSkipVerify: true
+  - name: public
+    baseUrl: https://galaxy.ansible.com
+`
+	cfg, err := parseRegistryConfig(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("parseRegistryConfig: %v", err)
+	}
+	if len(cfg.Upstreams) != 2 {
+		t.Fatalf("expected 2 upstreams, got %d", len(cfg.Upstreams))
+	}
+	if cfg.Upstreams[0].Name != "internal" || cfg.Upstreams[0].Auth.Type != "token" || cfg.Upstreams[0].Auth.Token != "s3cr3t" {
+		t.Fatalf("unexpected first upstream: %+v", cfg.Upstreams[0])
+	}
+	if !cfg.Upstreams[0].InsecureSkipVerify {
+		t.Fatal("expected insecureSkipVerify to be true for the first upstream")
+	}
+	if cfg.Upstreams[1].Name != "public" || cfg.Upstreams[1].Auth.Type != "" {
+		t.Fatalf("unexpected second upstream: %+v", cfg.Upstreams[1])
+	}
+}
+
+func TestNewRegistryFromConfigPreservesOrder(t *testing.T) {
+	cfg := RegistryConfig{Upstreams: []UpstreamConfig{
+		{Name: "internal", BaseURL: "https://galaxy.internal.example.com"},
+		{Name: "public", BaseURL: "https://galaxy.ansible.com"},
+	}}
+
+	reg, err := NewRegistryFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("NewRegistryFromConfig: %v", err)
+	}
+	if len(reg.Upstreams) != 2 {
+		t.Fatalf("expected 2 upstreams, got %d", len(reg.Upstreams))
+	}
+	if reg.Upstreams[0].Name != "internal" || reg.Upstreams[1].Name != "public" {
+		t.Fatalf("expected upstreams in declared order, got %+v", reg.Upstreams)
+	}
+}
+
+func TestNewRegistryFromConfigRejectsUnknownAuthType(t *testing.T) {
+	cfg := RegistryConfig{Upstreams: []UpstreamConfig{
+		{Name: "internal", Auth: UpstreamAuthConfig{Type: "kerberos"}},
+	}}
+
+	if _, err := NewRegistryFromConfig(cfg); err == nil {
+		t.Fatal("expected an error for an unknown auth type")
+	}
+}
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/atosatto/ansible-requirements-lint/requirements"
+)
+
+// Dispatch is a VersionsProvider that routes each Role/Collection to
+// Git or Galaxy depending on how it is sourced: entries pinned to a
+// repository (`scm: git`, or a `src`/`source` that looks like a URL)
+// are resolved through Git, everything else is resolved through
+// Galaxy. Galaxy is a VersionsProvider rather than a concrete
+// AnsibleGalaxy so a multi-mirror Registry can be plugged in as the
+// non-git branch.
+type Dispatch struct {
+	Galaxy VersionsProvider
+	Git    Git
+}
+
+// NewDispatch creates a new Dispatch provider.
+func NewDispatch(galaxy VersionsProvider) Dispatch {
+	return Dispatch{Galaxy: galaxy, Git: NewGit()}
+}
+
+// VersionsForRole resolves the versions available for the Role r.
+func (d Dispatch) VersionsForRole(ctx context.Context, r requirements.Role) ([]string, error) {
+	if isSCMSource(r.SCM, r.Source) {
+		return d.Git.VersionsForRole(ctx, r)
+	}
+	return d.Galaxy.VersionsForRole(ctx, r)
+}
+
+// VersionsForCollection resolves the versions available for the
+// Collection c.
+func (d Dispatch) VersionsForCollection(ctx context.Context, c requirements.Collection) ([]string, error) {
+	if isSCMSource("", c.Source) {
+		return d.Git.VersionsForCollection(ctx, c)
+	}
+	return d.Galaxy.VersionsForCollection(ctx, c)
+}
+
+// isSCMSource reports whether a requirement is pinned to a repository
+// rather than an Ansible Galaxy name: an explicit `scm: git`, or a
+// source that looks like a URL or SCM shorthand (git+https://, git@).
+func isSCMSource(scm, source string) bool {
+	if strings.EqualFold(scm, "git") {
+		return true
+	}
+	if source == "" {
+		return false
+	}
+	return strings.Contains(source, "://") || strings.HasPrefix(source, "git@")
+}
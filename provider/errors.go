@@ -0,0 +1,25 @@
+package provider
+
+import "fmt"
+
+// ErrRoleNotFound is returned when no role matching the requested
+// name could be found on the upstream Galaxy server.
+type ErrRoleNotFound struct {
+	Role string
+}
+
+func (e *ErrRoleNotFound) Error() string {
+	return fmt.Sprintf("unable to find role in Ansible Galaxy: %s", e.Role)
+}
+
+// ErrAmbiguousRole is returned when more than one candidate role
+// matches the requested name and none of them can be preferred over
+// the others.
+type ErrAmbiguousRole struct {
+	Role       string
+	Candidates []string
+}
+
+func (e *ErrAmbiguousRole) Error() string {
+	return fmt.Sprintf("ambiguous role %q on Ansible Galaxy, candidates: %v", e.Role, e.Candidates)
+}
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -13,24 +14,70 @@ import (
 	"github.com/atosatto/ansible-requirements-lint/requirements"
 )
 
+// collectionVersionsPath is the Galaxy v2/v3-compatible endpoint used to
+// list the versions published for a collection.
+const collectionVersionsPath = "/api/v2/collections/%s/%s/versions/"
+
 const (
 	// DefaultAnsibleGalaxyURL is the URL of the upstream Ansible Galaxy
 	// server managed by Red Hat.
 	DefaultAnsibleGalaxyURL = "https://galaxy.ansible.com"
 )
 
-// AnsibleGalaxy fetches Ansible Roles information
+// AuthType identifies how an AnsibleGalaxy provider authenticates
+// against its upstream server.
+type AuthType int
+
+const (
+	// AuthNone sends no authentication information. This is the
+	// default, appropriate for the public Ansible Galaxy.
+	AuthNone AuthType = iota
+	// AuthToken sends an `Authorization: Token <Token>` header, as
+	// used by Automation Hub and galaxy_ng based private mirrors.
+	AuthToken
+	// AuthBasic sends the request with HTTP basic authentication.
+	AuthBasic
+)
+
+// AuthConfig configures how a provider authenticates against its
+// upstream server.
+type AuthConfig struct {
+	Type     AuthType
+	Token    string
+	Username string
+	Password string
+}
+
+// apply sets the Authorization header (or basic auth) on req according
+// to the AuthConfig.
+func (a AuthConfig) apply(req *http.Request) {
+	switch a.Type {
+	case AuthToken:
+		req.Header.Set("Authorization", "Token "+a.Token)
+	case AuthBasic:
+		req.SetBasicAuth(a.Username, a.Password)
+	}
+}
+
+// AnsibleGalaxy fetches Ansible Roles and Collections information
 // for the Ansible Galaxy APIs.
 type AnsibleGalaxy struct {
-	baseURL string
+	baseURL   string
+	auth      AuthConfig
+	tlsConfig *tls.Config
+	transport http.RoundTripper
+	client    *http.Client
+	limiter   *RateLimiter
 }
 
 // NewAnsibleGalaxy creates a new AnsibleGalaxy provider.
 // If baseURL is a nil string, DefaulAnsibleGalaxyURL
 // will be used as baseURL for all the requests to the
-// AnsibleGalaxy APIs.
-func NewAnsibleGalaxy(baseURL string) AnsibleGalaxy {
-	g := AnsibleGalaxy{}
+// AnsibleGalaxy APIs. auth is used to authenticate against private
+// Galaxy or Automation Hub mirrors; the zero value performs
+// unauthenticated requests.
+func NewAnsibleGalaxy(baseURL string, auth AuthConfig) AnsibleGalaxy {
+	g := AnsibleGalaxy{auth: auth}
 	if baseURL == "" {
 		g.baseURL = DefaultAnsibleGalaxyURL
 	} else {
@@ -39,43 +86,181 @@ func NewAnsibleGalaxy(baseURL string) AnsibleGalaxy {
 	return g
 }
 
-// VersionsForRole returns the list of versions available on AnsibleGalaxy for the Role r.
-func (g AnsibleGalaxy) VersionsForRole(ctx context.Context, r requirements.Role) ([]string, error) {
+// WithTLSConfig returns a copy of g that dials its upstream server
+// using tlsConfig, e.g. to trust a private mirror's custom CA.
+func (g AnsibleGalaxy) WithTLSConfig(tlsConfig *tls.Config) AnsibleGalaxy {
+	g.tlsConfig = tlsConfig
+	return g
+}
+
+// WithHTTPClient returns a copy of g that issues its requests through
+// client instead of the default 10s-timeout client, so callers can
+// share a single tuned http.Client (custom transport, connection
+// pooling, ...) across every provider they construct.
+func (g AnsibleGalaxy) WithHTTPClient(client *http.Client) AnsibleGalaxy {
+	g.client = client
+	return g
+}
+
+// WithRateLimit returns a copy of g whose requests are throttled by
+// limiter, so a large requirements.yml can be resolved concurrently
+// without tripping the upstream's rate limiting.
+func (g AnsibleGalaxy) WithRateLimit(limiter *RateLimiter) AnsibleGalaxy {
+	g.limiter = limiter
+	return g
+}
+
+// WithRetry returns a copy of g that retries requests failing with a
+// 429 or 5xx response, up to maxRetries times, with exponential
+// backoff honoring any Retry-After header.
+func (g AnsibleGalaxy) WithRetry(maxRetries int) AnsibleGalaxy {
+	g.transport = NewRetryTransport(g.baseTransport(), maxRetries)
+	return g
+}
+
+// baseTransport returns the http.RoundTripper to build a client
+// around, honoring any transport chain (retry, cache, ...) or bare TLS
+// settings already configured on g.
+func (g AnsibleGalaxy) baseTransport() http.RoundTripper {
+	if g.transport != nil {
+		return g.transport
+	}
+	if g.tlsConfig != nil {
+		return &http.Transport{TLSClientConfig: g.tlsConfig}
+	}
+	return nil
+}
+
+// httpClient returns the http.Client to use for requests to this
+// upstream: an explicitly injected one via WithHTTPClient, or one
+// built from its TLS/cache/retry settings.
+func (g AnsibleGalaxy) httpClient() *http.Client {
+	if g.client != nil {
+		return g.client
+	}
 	client := &http.Client{Timeout: time.Second * 10}
+	client.Transport = g.baseTransport()
+	return client
+}
+
+// wait blocks until g's rate limiter (if any) admits the next request.
+func (g AnsibleGalaxy) wait(ctx context.Context) error {
+	if g.limiter == nil {
+		return nil
+	}
+	return g.limiter.Wait(ctx)
+}
+
+// galaxyRole is the subset of an Ansible Galaxy role representation
+// shared by the search and the roles list endpoints.
+type galaxyRole struct {
+	Name          string `json:"name"`
+	SummaryFields struct {
+		Versions []struct {
+			Name string `json:"name"`
+		} `json:"versions"`
+		Namespace struct {
+			Name string `json:"name"`
+		} `json:"namespace"`
+	} `json:"summary_fields"`
+}
+
+// VersionsForRole returns the list of versions available on AnsibleGalaxy for the Role r,
+// sorted in ascending order.
+//
+// When r is given in `namespace.name` form, it is looked up directly
+// via Galaxy's roles listing endpoint filtered by owner and name; this
+// falls back to a keyword search only if that direct lookup turns out
+// ambiguous. Roles given by a bare name (no namespace) always go
+// through the keyword search, since Galaxy has no per-name lookup.
+func (g AnsibleGalaxy) VersionsForRole(ctx context.Context, r requirements.Role) ([]string, error) {
+	keywords := r.Name
+	if len(keywords) == 0 {
+		keywords = r.Source
+	}
 
-	// Ansible Galaxy URL
-	baseURL, err := url.Parse(g.baseURL + "/api/v1/search/roles/")
+	namespace, name, exact := strings.Cut(keywords, ".")
+	if exact {
+		roles, err := g.lookupRoles(ctx, url.Values{
+			"owner__username": []string{namespace},
+			"name":            []string{name},
+		})
+		if err != nil {
+			return nil, err
+		}
+		switch len(roles) {
+		case 1:
+			return versionsOf(roles[0]), nil
+		case 0:
+			return nil, &ErrRoleNotFound{Role: keywords}
+		default:
+			// The owner+name filter should be unique; if the server
+			// still returns more than one match, fall back to the
+			// keyword search to try to disambiguate rather than
+			// giving up immediately.
+			return g.searchRoleVersions(ctx, keywords, namespace)
+		}
+	}
+
+	return g.searchRoleVersions(ctx, keywords, namespace)
+}
+
+// searchRoleVersions falls back to Galaxy's keyword search when a role
+// isn't given in unambiguous `namespace.name` form.
+func (g AnsibleGalaxy) searchRoleVersions(ctx context.Context, keywords, namespace string) ([]string, error) {
+	roles, err := g.lookupRoles(ctx, url.Values{"keywords": []string{keywords}})
 	if err != nil {
 		return nil, err
 	}
 
-	// keywords to be used for the search on Ansible Galaxy
-	var keywords string
-	if len(r.Source) != 0 {
-		keywords = r.Source
+	// search for the best match in the results list
+	var matching *galaxyRole
+	if len(roles) == 1 {
+		matching = &roles[0]
 	} else {
-		keywords = r.Name
+		for i := range roles {
+			if roles[i].SummaryFields.Namespace.Name == namespace {
+				matching = &roles[i]
+				break
+			}
+		}
 	}
+	if matching == nil {
+		if len(roles) > 1 {
+			return nil, &ErrAmbiguousRole{Role: keywords, Candidates: roleNames(roles)}
+		}
+		return nil, &ErrRoleNotFound{Role: keywords}
+	}
+	return versionsOf(*matching), nil
+}
+
+// lookupRoles queries an Ansible Galaxy roles endpoint with the given
+// filter parameters and returns the matching roles.
+func (g AnsibleGalaxy) lookupRoles(ctx context.Context, params url.Values) ([]galaxyRole, error) {
+	if err := g.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	client := g.httpClient()
 
-	// namespace to be used to filter the Ansible Galaxy results
-	var namespace string
-	split := strings.Split(keywords, ".")
-	if len(split) > 0 {
-		namespace = split[0]
+	endpoint := "/api/v1/roles/"
+	if params.Get("keywords") != "" {
+		endpoint = "/api/v1/search/roles/"
 	}
 
-	// set the Ansible Galaxy search parameters
-	params := url.Values{}
-	params.Add("keywords", keywords)
+	baseURL, err := url.Parse(g.baseURL + endpoint)
+	if err != nil {
+		return nil, err
+	}
 	baseURL.RawQuery = params.Encode()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", baseURL.String(), nil)
 	if err != nil {
 		return nil, err
 	}
-
 	req.Header.Set("User-Agent", "ansible-requirements-lint")
 	req.Header.Add("Accept", "application/json")
+	g.auth.apply(req)
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -83,7 +268,7 @@ func (g AnsibleGalaxy) VersionsForRole(ctx context.Context, r requirements.Role)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode < 200 && resp.StatusCode >= 300 {
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return nil, fmt.Errorf("unexpected Ansible Galaxy response code: %d", resp.StatusCode)
 	}
 
@@ -92,46 +277,110 @@ func (g AnsibleGalaxy) VersionsForRole(ctx context.Context, r requirements.Role)
 		return nil, err
 	}
 
-	type galaxyResult struct {
-		SummaryFields struct {
-			Versions []struct {
-				Name string `json:"name"`
-			} `json:"versions"`
-			Namespace struct {
-				Name string `json:"name"`
-			} `json:"namespace"`
-		} `json:"summary_fields"`
+	var results struct {
+		Count   int          `json:"count"`
+		Results []galaxyRole `json:"results"`
 	}
+	if err := json.Unmarshal(body, &results); err != nil {
+		return nil, err
+	}
+	return results.Results, nil
+}
 
-	var results struct {
-		Count   int            `json:"count"`
-		Results []galaxyResult `json:"results"`
+func versionsOf(r galaxyRole) []string {
+	versions := make([]string, len(r.SummaryFields.Versions))
+	for i, v := range r.SummaryFields.Versions {
+		versions[i] = v.Name
+	}
+	sortVersions(versions)
+	return versions
+}
+
+func roleNames(roles []galaxyRole) []string {
+	names := make([]string, len(roles))
+	for i, r := range roles {
+		names[i] = r.SummaryFields.Namespace.Name + "." + r.Name
 	}
-	err = json.Unmarshal(body, &results)
+	return names
+}
+
+// VersionsForCollection returns the list of versions available on
+// Ansible Galaxy (or an Automation Hub compatible server) for the
+// Collection c, sorted in ascending order.
+func (g AnsibleGalaxy) VersionsForCollection(ctx context.Context, c requirements.Collection) ([]string, error) {
+	namespace, name, err := splitCollectionName(c.Name)
 	if err != nil {
 		return nil, err
 	}
 
-	// search for the best match in the results list
-	var matching *galaxyResult
-	if len(results.Results) == 1 {
-		matching = &results.Results[0]
-	} else {
-		for _, r := range results.Results {
-			if r.SummaryFields.Namespace.Name == namespace {
-				matching = &r
-				break
-			}
+	client := g.httpClient()
+
+	next := g.baseURL + fmt.Sprintf(collectionVersionsPath, namespace, name)
+	var versions []string
+	for next != "" {
+		req, err := http.NewRequestWithContext(ctx, "GET", next, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("User-Agent", "ansible-requirements-lint")
+		req.Header.Add("Accept", "application/json")
+		g.auth.apply(req)
+
+		if err := g.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return nil, fmt.Errorf("unexpected Ansible Galaxy response code: %d", resp.StatusCode)
+		}
+
+		var page struct {
+			Results []struct {
+				Version string `json:"version"`
+			} `json:"data"`
+			Links struct {
+				Next string `json:"next"`
+			} `json:"links"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, err
+		}
+
+		for _, r := range page.Results {
+			versions = append(versions, r.Version)
+		}
+
+		next = page.Links.Next
+		if next != "" && !strings.HasPrefix(next, "http") {
+			next = g.baseURL + next
 		}
-	}
-	if matching == nil {
-		return nil, fmt.Errorf("unable to find role in Ansible Galaxy: %s", keywords)
 	}
 
-	// get the latest version of the role
-	versions := make([]string, len(matching.SummaryFields.Versions))
-	for i, v := range matching.SummaryFields.Versions {
-		versions[i] = v.Name
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("unable to find collection in Ansible Galaxy: %s", c.Name)
 	}
+
+	sortVersions(versions)
 	return versions, nil
 }
+
+// splitCollectionName splits a `namespace.name` collection identifier
+// into its two parts.
+func splitCollectionName(fqcn string) (namespace, name string, err error) {
+	parts := strings.SplitN(fqcn, ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid collection name, expected namespace.name: %s", fqcn)
+	}
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/atosatto/ansible-requirements-lint/requirements"
+)
+
+type fakeGalaxyRole struct {
+	Name          string `json:"name"`
+	SummaryFields struct {
+		Versions []struct {
+			Name string `json:"name"`
+		} `json:"versions"`
+		Namespace struct {
+			Name string `json:"name"`
+		} `json:"namespace"`
+	} `json:"summary_fields"`
+}
+
+func roleFixture(namespace, name string, versions ...string) fakeGalaxyRole {
+	r := fakeGalaxyRole{Name: name}
+	r.SummaryFields.Namespace.Name = namespace
+	for _, v := range versions {
+		r.SummaryFields.Versions = append(r.SummaryFields.Versions, struct {
+			Name string `json:"name"`
+		}{Name: v})
+	}
+	return r
+}
+
+func writeResults(w http.ResponseWriter, roles []fakeGalaxyRole) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(struct {
+		Count   int              `json:"count"`
+		Results []fakeGalaxyRole `json:"results"`
+	}{Count: len(roles), Results: roles})
+}
+
+func TestVersionsForRoleExactMatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/roles/" {
+			t.Fatalf("expected the direct roles endpoint, got %s", r.URL.Path)
+		}
+		if got, want := r.URL.Query().Get("owner__username"), "geerlingguy"; got != want {
+			t.Fatalf("owner__username = %q, want %q", got, want)
+		}
+		if got, want := r.URL.Query().Get("name"), "docker"; got != want {
+			t.Fatalf("name = %q, want %q", got, want)
+		}
+		writeResults(w, []fakeGalaxyRole{roleFixture("geerlingguy", "docker", "1.0.0", "2.0.0")})
+	}))
+	defer srv.Close()
+
+	g := NewAnsibleGalaxy(srv.URL, AuthConfig{})
+	versions, err := g.VersionsForRole(context.Background(), requirements.Role{Name: "geerlingguy.docker"})
+	if err != nil {
+		t.Fatalf("VersionsForRole: %v", err)
+	}
+	if len(versions) != 2 || versions[0] != "1.0.0" || versions[1] != "2.0.0" {
+		t.Fatalf("unexpected versions: %v", versions)
+	}
+}
+
+func TestVersionsForRoleExactMatchNotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeResults(w, nil)
+	}))
+	defer srv.Close()
+
+	g := NewAnsibleGalaxy(srv.URL, AuthConfig{})
+	_, err := g.VersionsForRole(context.Background(), requirements.Role{Name: "geerlingguy.docker"})
+
+	var notFound *ErrRoleNotFound
+	if !errors.As(err, &notFound) {
+		t.Fatalf("expected ErrRoleNotFound, got %v (%T)", err, err)
+	}
+}
+
+func TestVersionsForRoleFallsBackToSearchWhenExactLookupIsAmbiguous(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/roles/":
+			// The owner+name filter unexpectedly returns more than
+			// one candidate.
+			writeResults(w, []fakeGalaxyRole{
+				roleFixture("geerlingguy", "docker", "1.0.0"),
+				roleFixture("someoneelse", "docker", "9.9.9"),
+			})
+		case "/api/v1/search/roles/":
+			writeResults(w, []fakeGalaxyRole{
+				roleFixture("geerlingguy", "docker", "1.0.0"),
+				roleFixture("someoneelse", "docker", "9.9.9"),
+			})
+		default:
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	g := NewAnsibleGalaxy(srv.URL, AuthConfig{})
+	versions, err := g.VersionsForRole(context.Background(), requirements.Role{Name: "geerlingguy.docker"})
+	if err != nil {
+		t.Fatalf("VersionsForRole: %v", err)
+	}
+	if len(versions) != 1 || versions[0] != "1.0.0" {
+		t.Fatalf("expected the search fallback to disambiguate by namespace, got %v", versions)
+	}
+}
+
+func TestVersionsForRoleAmbiguousAfterFallback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Neither endpoint can tell the namespaces apart from the
+		// requested one.
+		writeResults(w, []fakeGalaxyRole{
+			roleFixture("other1", "docker", "1.0.0"),
+			roleFixture("other2", "docker", "2.0.0"),
+		})
+	}))
+	defer srv.Close()
+
+	g := NewAnsibleGalaxy(srv.URL, AuthConfig{})
+	_, err := g.VersionsForRole(context.Background(), requirements.Role{Name: "geerlingguy.docker"})
+
+	var ambiguous *ErrAmbiguousRole
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("expected ErrAmbiguousRole, got %v (%T)", err, err)
+	}
+	if len(ambiguous.Candidates) != 2 {
+		t.Fatalf("expected 2 candidates, got %v", ambiguous.Candidates)
+	}
+}
+
+func TestVersionsForRoleBareNameUsesSearch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/search/roles/" {
+			t.Fatalf("expected a bare name to go straight to search, got %s", r.URL.Path)
+		}
+		writeResults(w, []fakeGalaxyRole{roleFixture("geerlingguy", "docker", "1.0.0")})
+	}))
+	defer srv.Close()
+
+	g := NewAnsibleGalaxy(srv.URL, AuthConfig{})
+	versions, err := g.VersionsForRole(context.Background(), requirements.Role{Name: "docker"})
+	if err != nil {
+		t.Fatalf("VersionsForRole: %v", err)
+	}
+	if len(versions) != 1 || versions[0] != "1.0.0" {
+		t.Fatalf("unexpected versions: %v", versions)
+	}
+}
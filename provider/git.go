@@ -0,0 +1,149 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/atosatto/ansible-requirements-lint/requirements"
+)
+
+// tagVersionRE extracts a semver-like version out of a git tag name,
+// stripping a common "v" prefix (e.g. "v1.2.3" or "1.2.3").
+var tagVersionRE = regexp.MustCompile(`^v?(\d+(?:\.\d+)*(?:-[0-9A-Za-z.-]+)?)$`)
+
+// scpLikeRE matches the scp-like SSH syntax git accepts without a URL
+// scheme, e.g. "git@github.com:owner/repo.git".
+var scpLikeRE = regexp.MustCompile(`^[\w.-]+@[\w.-]+:`)
+
+// allowedGitSchemes are the URL schemes git ls-remote is permitted to
+// dial. Everything else — notably git's "ext::"/"fd::" transport
+// helpers, which exec their argument, and "file://" — is rejected so a
+// requirements.yml entry can't be turned into arbitrary command
+// execution.
+var allowedGitSchemes = map[string]struct{}{
+	"http":  {},
+	"https": {},
+	"ssh":   {},
+	"git":   {},
+}
+
+// validateGitSource rejects any source that isn't a plain http(s)/ssh/git
+// URL (or scp-like SSH shorthand), and any source that could be
+// mistaken for a command-line flag by git.
+func validateGitSource(source string) error {
+	if source == "" {
+		return fmt.Errorf("unable to list tags: no git source given")
+	}
+	if strings.HasPrefix(source, "-") {
+		return fmt.Errorf("invalid git source %q: must not start with '-'", source)
+	}
+	if scpLikeRE.MatchString(source) {
+		return nil
+	}
+
+	u, err := url.Parse(source)
+	if err != nil {
+		return fmt.Errorf("invalid git source %q: %w", source, err)
+	}
+	if _, ok := allowedGitSchemes[strings.ToLower(u.Scheme)]; !ok {
+		return fmt.Errorf("invalid git source %q: unsupported scheme %q (only http, https, ssh and git are allowed)", source, u.Scheme)
+	}
+	return nil
+}
+
+// Git fetches versions for Roles and Collections that are pinned to a
+// git repository (`src`/`source: https://...` or `scm: git`) rather
+// than to an Ansible Galaxy name, by listing the repository's tags.
+type Git struct{}
+
+// NewGit creates a new Git provider.
+func NewGit() Git {
+	return Git{}
+}
+
+// VersionsForRole returns the list of versions, derived from the tags
+// of the repository pointed to by r.Source, sorted in ascending order.
+func (g Git) VersionsForRole(ctx context.Context, r requirements.Role) ([]string, error) {
+	return versionsFromTags(ctx, r.Source)
+}
+
+// VersionsForCollection returns the list of versions, derived from the
+// tags of the repository pointed to by c.Source, sorted in ascending
+// order.
+func (g Git) VersionsForCollection(ctx context.Context, c requirements.Collection) ([]string, error) {
+	return versionsFromTags(ctx, c.Source)
+}
+
+func versionsFromTags(ctx context.Context, source string) ([]string, error) {
+	if err := validateGitSource(source); err != nil {
+		return nil, err
+	}
+	return fetchTags(ctx, source)
+}
+
+// fetchTags runs `git ls-remote --tags` against source and parses the
+// result into a deduped, sorted list of versions. It performs no
+// validation of source itself — callers reachable from
+// requirements.yml data MUST go through validateGitSource first so
+// that git's "ext::"/"fd::" transport helpers can't be used to execute
+// arbitrary commands. It is split out from versionsFromTags so tests
+// can exercise the fetch-and-parse behavior against a local repository
+// without relaxing that check.
+func fetchTags(ctx context.Context, source string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", "ls-remote", "--tags", "--", source)
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("unable to list tags for %s: %w", source, err)
+	}
+
+	var versions []string
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		ref := strings.TrimSuffix(fields[1], "^{}")
+		ref = strings.TrimPrefix(ref, "refs/tags/")
+
+		m := tagVersionRE.FindStringSubmatch(ref)
+		if m == nil {
+			continue
+		}
+		versions = append(versions, m[1])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := cmd.Wait(); err != nil {
+		return nil, fmt.Errorf("unable to list tags for %s: %w", source, err)
+	}
+
+	versions = dedupeVersions(versions)
+	sortVersions(versions)
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no tagged versions found for %s", source)
+	}
+	return versions, nil
+}
+
+func dedupeVersions(versions []string) []string {
+	seen := make(map[string]struct{}, len(versions))
+	deduped := versions[:0]
+	for _, v := range versions {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		deduped = append(deduped, v)
+	}
+	return deduped
+}
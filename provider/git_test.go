@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"testing"
+)
+
+func TestValidateGitSource(t *testing.T) {
+	valid := []string{
+		"https://github.com/geerlingguy/ansible-role-docker.git",
+		"http://internal.example.com/roles/docker.git",
+		"ssh://git@github.com/geerlingguy/ansible-role-docker.git",
+		"git@github.com:geerlingguy/ansible-role-docker.git",
+		"git://github.com/geerlingguy/ansible-role-docker.git",
+	}
+	for _, source := range valid {
+		if err := validateGitSource(source); err != nil {
+			t.Errorf("validateGitSource(%q) = %v, want nil", source, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"ext::sh -c 'touch pwned'",
+		"fd::1",
+		"file:///etc/passwd",
+		"--upload-pack=touch pwned",
+		"-x",
+	}
+	for _, source := range invalid {
+		if err := validateGitSource(source); err == nil {
+			t.Errorf("validateGitSource(%q) = nil, want error", source)
+		}
+	}
+}
+
+// initGitRepoWithTags creates a local git repository with a handful of
+// tags, some of which don't look like versions, to exercise fetchTags'
+// parsing and dedupe logic end to end against a real `git ls-remote`.
+func initGitRepoWithTags(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(cmd.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	run("init", "--initial-branch=main")
+	run("commit", "--allow-empty", "-m", "initial commit")
+	run("tag", "v1.0.0")
+	run("tag", "1.2.0")
+	run("tag", "v1.2.0")
+	run("tag", "not-a-version")
+	run("tag", "v1.10.0")
+
+	return dir
+}
+
+func TestFetchTagsParsesAndDedupesRealRepository(t *testing.T) {
+	dir := initGitRepoWithTags(t)
+
+	versions, err := fetchTags(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("fetchTags: %v", err)
+	}
+
+	want := []string{"1.0.0", "1.2.0", "1.10.0"}
+	if fmt.Sprint(versions) != fmt.Sprint(want) {
+		t.Fatalf("expected numerically sorted, deduped, version-only tags %v, got %v", want, versions)
+	}
+}
@@ -0,0 +1,14 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/atosatto/ansible-requirements-lint/requirements"
+)
+
+// VersionsProvider resolves the versions available for a Role or a
+// Collection declared in a requirements.yml file.
+type VersionsProvider interface {
+	VersionsForRole(ctx context.Context, r requirements.Role) ([]string, error)
+	VersionsForCollection(ctx context.Context, c requirements.Collection) ([]string, error)
+}
@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimiter is a simple token-bucket limiter shared across every
+// provider request so a large requirements.yml resolved concurrently
+// doesn't overrun the upstream's own rate limits.
+type RateLimiter struct {
+	tokens chan struct{}
+	stop   chan struct{}
+}
+
+// NewRateLimiter creates a RateLimiter that admits up to burst
+// requests immediately and refills at rps requests per second
+// thereafter.
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	rl := &RateLimiter{
+		tokens: make(chan struct{}, burst),
+		stop:   make(chan struct{}),
+	}
+	for i := 0; i < burst; i++ {
+		rl.tokens <- struct{}{}
+	}
+
+	if rps > 0 {
+		interval := time.Duration(float64(time.Second) / rps)
+		go rl.refill(interval)
+	}
+	return rl
+}
+
+func (rl *RateLimiter) refill(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			select {
+			case rl.tokens <- struct{}{}:
+			default:
+			}
+		case <-rl.stop:
+			return
+		}
+	}
+}
+
+// Wait blocks until a token is available or ctx is done.
+func (rl *RateLimiter) Wait(ctx context.Context) error {
+	select {
+	case <-rl.tokens:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops the RateLimiter's background refill goroutine.
+func (rl *RateLimiter) Close() {
+	close(rl.stop)
+}
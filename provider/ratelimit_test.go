@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterAllowsBurstImmediately(t *testing.T) {
+	rl := NewRateLimiter(0, 3)
+	defer rl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	for i := 0; i < 3; i++ {
+		if err := rl.Wait(ctx); err != nil {
+			t.Fatalf("burst request %d: %v", i, err)
+		}
+	}
+}
+
+func TestRateLimiterBlocksOnceBurstIsExhausted(t *testing.T) {
+	rl := NewRateLimiter(0, 1) // no refill: rps <= 0 disables the refill goroutine
+	defer rl.Close()
+
+	ctx := context.Background()
+	if err := rl.Wait(ctx); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+
+	timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := rl.Wait(timeout); err == nil {
+		t.Fatal("expected the second request to block past the context deadline")
+	}
+}
+
+func TestRateLimiterRefillsOverTime(t *testing.T) {
+	rl := NewRateLimiter(1000, 1) // ~1ms between tokens
+	defer rl.Close()
+
+	ctx := context.Background()
+	if err := rl.Wait(ctx); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+
+	timeout, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	if err := rl.Wait(timeout); err != nil {
+		t.Fatalf("expected a token to be refilled within a second, got: %v", err)
+	}
+}
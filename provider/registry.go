@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/atosatto/ansible-requirements-lint/requirements"
+)
+
+// Upstream is a single Galaxy-compatible server registered with a
+// Registry, such as a private galaxy_ng/Automation Hub mirror or the
+// public Ansible Galaxy.
+type Upstream struct {
+	// Name identifies the upstream in error messages, e.g. "internal".
+	Name     string
+	Provider VersionsProvider
+}
+
+// Registry queries a set of Galaxy-compatible upstreams in priority
+// order, merging the version lists they return. This lets users lint
+// requirements that mix roles/collections hosted on a private mirror
+// with ones hosted on the public Ansible Galaxy.
+//
+// Upstreams are tried in the order they were added; the first
+// upstream to know about a requirement is the highest priority one.
+type Registry struct {
+	Upstreams []Upstream
+}
+
+// NewRegistry creates a Registry with no upstreams configured.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Add registers an upstream, in priority order.
+func (r *Registry) Add(name string, provider VersionsProvider) {
+	r.Upstreams = append(r.Upstreams, Upstream{Name: name, Provider: provider})
+}
+
+// VersionsForRole returns the versions available for the Role r,
+// merged across every upstream that recognizes it.
+func (r *Registry) VersionsForRole(ctx context.Context, role requirements.Role) ([]string, error) {
+	return r.merge(func(p VersionsProvider) ([]string, error) {
+		return p.VersionsForRole(ctx, role)
+	})
+}
+
+// VersionsForCollection returns the versions available for the
+// Collection c, merged across every upstream that recognizes it.
+func (r *Registry) VersionsForCollection(ctx context.Context, c requirements.Collection) ([]string, error) {
+	return r.merge(func(p VersionsProvider) ([]string, error) {
+		return p.VersionsForCollection(ctx, c)
+	})
+}
+
+func (r *Registry) merge(lookup func(VersionsProvider) ([]string, error)) ([]string, error) {
+	seen := make(map[string]struct{})
+	var merged []string
+	var firstErr error
+	for _, u := range r.Upstreams {
+		versions, err := lookup(u.Provider)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		for _, v := range versions {
+			if _, ok := seen[v]; ok {
+				continue
+			}
+			seen[v] = struct{}{}
+			merged = append(merged, v)
+		}
+	}
+	if len(merged) == 0 {
+		return nil, firstErr
+	}
+	sortVersions(merged)
+	return merged, nil
+}
@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/atosatto/ansible-requirements-lint/requirements"
+)
+
+type stubProvider struct {
+	roleVersions       []string
+	roleErr            error
+	collectionVersions []string
+	collectionErr      error
+}
+
+func (p *stubProvider) VersionsForRole(ctx context.Context, r requirements.Role) ([]string, error) {
+	return p.roleVersions, p.roleErr
+}
+
+func (p *stubProvider) VersionsForCollection(ctx context.Context, c requirements.Collection) ([]string, error) {
+	return p.collectionVersions, p.collectionErr
+}
+
+func TestRegistryMergesAndDedupesAcrossUpstreams(t *testing.T) {
+	reg := NewRegistry()
+	reg.Add("internal", &stubProvider{roleVersions: []string{"1.0.0", "1.2.0"}})
+	reg.Add("public", &stubProvider{roleVersions: []string{"1.2.0", "1.4.0"}})
+
+	versions, err := reg.VersionsForRole(context.Background(), requirements.Role{Name: "ns.role"})
+	if err != nil {
+		t.Fatalf("VersionsForRole: %v", err)
+	}
+	want := []string{"1.0.0", "1.2.0", "1.4.0"}
+	if fmt.Sprint(versions) != fmt.Sprint(want) {
+		t.Fatalf("expected deduped, sorted %v, got %v", want, versions)
+	}
+}
+
+func TestRegistrySucceedsWhenAHigherPriorityUpstreamFails(t *testing.T) {
+	reg := NewRegistry()
+	reg.Add("internal", &stubProvider{roleErr: errors.New("internal mirror down")})
+	reg.Add("public", &stubProvider{roleVersions: []string{"2.0.0"}})
+
+	versions, err := reg.VersionsForRole(context.Background(), requirements.Role{Name: "ns.role"})
+	if err != nil {
+		t.Fatalf("expected the public upstream's result to be used, got error: %v", err)
+	}
+	if fmt.Sprint(versions) != fmt.Sprint([]string{"2.0.0"}) {
+		t.Fatalf("unexpected versions: %v", versions)
+	}
+}
+
+func TestRegistryPreservesFirstErrorWhenEveryUpstreamFails(t *testing.T) {
+	firstErr := &ErrAmbiguousRole{Role: "ns.role", Candidates: []string{"ns.role", "other.role"}}
+	lastErr := &ErrRoleNotFound{Role: "ns.role"}
+
+	reg := NewRegistry()
+	reg.Add("internal", &stubProvider{roleErr: firstErr})
+	reg.Add("public", &stubProvider{roleErr: lastErr})
+
+	_, err := reg.VersionsForRole(context.Background(), requirements.Role{Name: "ns.role"})
+	if err != firstErr {
+		t.Fatalf("expected the first upstream's error to be preserved, got %v", err)
+	}
+}
+
+func TestRegistryCollections(t *testing.T) {
+	reg := NewRegistry()
+	reg.Add("only", &stubProvider{collectionVersions: []string{"3.0.0", "3.1.0"}})
+
+	versions, err := reg.VersionsForCollection(context.Background(), requirements.Collection{Name: "ns.collection"})
+	if err != nil {
+		t.Fatalf("VersionsForCollection: %v", err)
+	}
+	if fmt.Sprint(versions) != fmt.Sprint([]string{"3.0.0", "3.1.0"}) {
+		t.Fatalf("unexpected versions: %v", versions)
+	}
+}
+
+func TestRegistryNoUpstreamsReturnsNoResultsAndNoError(t *testing.T) {
+	reg := NewRegistry()
+	versions, err := reg.VersionsForRole(context.Background(), requirements.Role{Name: "ns.role"})
+	if err != nil {
+		t.Fatalf("expected no error with zero upstreams, got %v", err)
+	}
+	if len(versions) != 0 {
+		t.Fatalf("expected no versions, got %v", versions)
+	}
+}
@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/atosatto/ansible-requirements-lint/requirements"
+)
+
+// DefaultConcurrency is how many roles/collections are resolved in
+// parallel when a Resolve caller does not set ResolveOptions.Concurrency.
+const DefaultConcurrency = 8
+
+// Resolution is the outcome of resolving the versions available for a
+// single Role or Collection entry from a requirements.yml file.
+type Resolution struct {
+	Role       *requirements.Role
+	Collection *requirements.Collection
+	Versions   []string
+	Err        error
+}
+
+// ResolveOptions tunes how Resolve fans requests out to the provider.
+type ResolveOptions struct {
+	// Concurrency is the maximum number of in-flight requests to the
+	// provider. DefaultConcurrency is used if unset.
+	Concurrency int
+}
+
+// Resolve looks up the available versions for every Role and
+// Collection in reqs using p, up to opts.Concurrency requests at a
+// time. Per-entry errors are recorded on the corresponding Resolution
+// rather than aborting the whole run; Resolve itself only returns an
+// error if the context is canceled.
+func Resolve(ctx context.Context, p VersionsProvider, reqs requirements.Requirements, opts ResolveOptions) ([]Resolution, error) {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	results := make([]Resolution, len(reqs.Roles)+len(reqs.Collections))
+
+	g, gctx := errgroup.WithContext(ctx)
+	sem := make(chan struct{}, concurrency)
+
+	for i := range reqs.Roles {
+		i, role := i, reqs.Roles[i]
+		g.Go(func() error {
+			select {
+			case sem <- struct{}{}:
+			case <-gctx.Done():
+				return gctx.Err()
+			}
+			defer func() { <-sem }()
+
+			versions, err := p.VersionsForRole(gctx, role)
+			results[i] = Resolution{Role: &reqs.Roles[i], Versions: versions, Err: err}
+			return nil
+		})
+	}
+
+	offset := len(reqs.Roles)
+	for i := range reqs.Collections {
+		i, collection := i, reqs.Collections[i]
+		g.Go(func() error {
+			select {
+			case sem <- struct{}{}:
+			case <-gctx.Done():
+				return gctx.Err()
+			}
+			defer func() { <-sem }()
+
+			versions, err := p.VersionsForCollection(gctx, collection)
+			results[offset+i] = Resolution{Collection: &reqs.Collections[i], Versions: versions, Err: err}
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
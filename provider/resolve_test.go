@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/atosatto/ansible-requirements-lint/requirements"
+)
+
+// trackingProvider records how many calls are in flight at once and
+// optionally fails a subset of roles by name.
+type trackingProvider struct {
+	delay       time.Duration
+	failRoles   map[string]bool
+	inFlight    int32
+	maxInFlight int32
+}
+
+func (p *trackingProvider) enter() func() {
+	n := atomic.AddInt32(&p.inFlight, 1)
+	for {
+		max := atomic.LoadInt32(&p.maxInFlight)
+		if n <= max || atomic.CompareAndSwapInt32(&p.maxInFlight, max, n) {
+			break
+		}
+	}
+	return func() { atomic.AddInt32(&p.inFlight, -1) }
+}
+
+func (p *trackingProvider) VersionsForRole(ctx context.Context, r requirements.Role) ([]string, error) {
+	defer p.enter()()
+	time.Sleep(p.delay)
+	if p.failRoles[r.Name] {
+		return nil, fmt.Errorf("boom: %s", r.Name)
+	}
+	return []string{"1.0.0"}, nil
+}
+
+func (p *trackingProvider) VersionsForCollection(ctx context.Context, c requirements.Collection) ([]string, error) {
+	defer p.enter()()
+	time.Sleep(p.delay)
+	return []string{"2.0.0"}, nil
+}
+
+func TestResolveBoundsConcurrency(t *testing.T) {
+	reqs := requirements.Requirements{}
+	for i := 0; i < 20; i++ {
+		reqs.Roles = append(reqs.Roles, requirements.Role{Name: fmt.Sprintf("ns.role%d", i)})
+	}
+	p := &trackingProvider{delay: 5 * time.Millisecond}
+
+	results, err := Resolve(context.Background(), p, reqs, ResolveOptions{Concurrency: 4})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if len(results) != 20 {
+		t.Fatalf("expected 20 results, got %d", len(results))
+	}
+	if p.maxInFlight > 4 {
+		t.Fatalf("expected at most 4 in-flight requests, saw %d", p.maxInFlight)
+	}
+}
+
+func TestResolveRecordsPerEntryErrors(t *testing.T) {
+	reqs := requirements.Requirements{
+		Roles: []requirements.Role{
+			{Name: "ns.good"},
+			{Name: "ns.bad"},
+		},
+	}
+	p := &trackingProvider{failRoles: map[string]bool{"ns.bad": true}}
+
+	results, err := Resolve(context.Background(), p, reqs, ResolveOptions{})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+
+	var sawGood, sawBad bool
+	for _, r := range results {
+		switch r.Role.Name {
+		case "ns.good":
+			sawGood = true
+			if r.Err != nil {
+				t.Fatalf("expected ns.good to succeed, got %v", r.Err)
+			}
+		case "ns.bad":
+			sawBad = true
+			if r.Err == nil {
+				t.Fatal("expected ns.bad to record an error")
+			}
+		}
+	}
+	if !sawGood || !sawBad {
+		t.Fatalf("expected results for both roles, got %+v", results)
+	}
+}
+
+func TestResolveHandlesRolesAndCollections(t *testing.T) {
+	reqs := requirements.Requirements{
+		Roles:       []requirements.Role{{Name: "ns.role"}},
+		Collections: []requirements.Collection{{Name: "ns.collection"}},
+	}
+	p := &trackingProvider{}
+
+	results, err := Resolve(context.Background(), p, reqs, ResolveOptions{})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	var sawRole, sawCollection bool
+	for _, r := range results {
+		if r.Role != nil {
+			sawRole = true
+		}
+		if r.Collection != nil {
+			sawCollection = true
+		}
+	}
+	if !sawRole || !sawCollection {
+		t.Fatalf("expected both a role and a collection result, got %+v", results)
+	}
+}
+
+func TestResolveAbortsOnContextCancellation(t *testing.T) {
+	reqs := requirements.Requirements{}
+	for i := 0; i < 5; i++ {
+		reqs.Roles = append(reqs.Roles, requirements.Role{Name: fmt.Sprintf("ns.role%d", i)})
+	}
+	p := &trackingProvider{delay: 50 * time.Millisecond}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := Resolve(ctx, p, reqs, ResolveOptions{Concurrency: 1}); err == nil {
+		t.Fatal("expected Resolve to return an error for an already-canceled context")
+	}
+}
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultRetryBaseDelay is the starting delay RetryTransport backs off
+// from on a 429/5xx response.
+const DefaultRetryBaseDelay = 500 * time.Millisecond
+
+// RetryTransport is an http.RoundTripper that retries requests which
+// fail with a 429 or 5xx response, honoring a `Retry-After` header
+// when present and otherwise backing off exponentially with jitter.
+type RetryTransport struct {
+	// Base is the underlying transport. http.DefaultTransport is used
+	// if nil.
+	Base http.RoundTripper
+	// MaxRetries is how many additional attempts are made after the
+	// first one fails.
+	MaxRetries int
+	// BaseDelay is the delay before the first retry; it doubles on
+	// each subsequent attempt.
+	BaseDelay time.Duration
+}
+
+// NewRetryTransport creates a RetryTransport wrapping base (or
+// http.DefaultTransport if nil) with maxRetries retries.
+func NewRetryTransport(base http.RoundTripper, maxRetries int) *RetryTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &RetryTransport{Base: base, MaxRetries: maxRetries, BaseDelay: DefaultRetryBaseDelay}
+}
+
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	var resp *http.Response
+	var err error
+	for attempt := 0; ; attempt++ {
+		resp, err = base.RoundTrip(req)
+		if err != nil || !isRetryable(resp) || attempt >= t.MaxRetries {
+			return resp, err
+		}
+
+		delay := retryDelay(resp, attempt, t.BaseDelay)
+		resp.Body.Close()
+
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+func isRetryable(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// retryDelay honors a Retry-After header (seconds or HTTP date) when
+// present, otherwise backs off exponentially from base with up to 20%
+// jitter.
+func retryDelay(resp *http.Response, attempt int, base time.Duration) time.Duration {
+	if ra := resp.Header.Get("Retry-After"); ra != "" {
+		if secs, err := strconv.Atoi(ra); err == nil {
+			return time.Duration(secs) * time.Second
+		}
+		if when, err := http.ParseTime(ra); err == nil {
+			if d := time.Until(when); d > 0 {
+				return d
+			}
+		}
+	}
+
+	backoff := base << attempt
+	jitter := time.Duration(rand.Int63n(int64(backoff) / 5 + 1))
+	return backoff + jitter
+}
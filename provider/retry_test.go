@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryTransportRetriesUntilSuccess(t *testing.T) {
+	stub := &stubTransport{responses: []*http.Response{
+		newResponse(http.StatusServiceUnavailable, "", nil),
+		newResponse(http.StatusTooManyRequests, "", nil),
+		newResponse(http.StatusOK, "ok", nil),
+	}}
+	rt := NewRetryTransport(stub, 5)
+	rt.BaseDelay = time.Millisecond
+
+	req, _ := http.NewRequest(http.MethodGet, "https://galaxy.example.com/api/v1/roles/", nil)
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected eventual 200, got %d", resp.StatusCode)
+	}
+	if len(stub.requests) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(stub.requests))
+	}
+}
+
+func TestRetryTransportGivesUpAfterMaxRetries(t *testing.T) {
+	stub := &stubTransport{responses: []*http.Response{
+		newResponse(http.StatusServiceUnavailable, "", nil),
+		newResponse(http.StatusServiceUnavailable, "", nil),
+	}}
+	rt := NewRetryTransport(stub, 1)
+	rt.BaseDelay = time.Millisecond
+
+	req, _ := http.NewRequest(http.MethodGet, "https://galaxy.example.com/api/v1/roles/", nil)
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected the last failing response after exhausting retries, got %d", resp.StatusCode)
+	}
+	if len(stub.requests) != 2 {
+		t.Fatalf("expected 1 initial attempt + 1 retry, got %d", len(stub.requests))
+	}
+}
+
+func TestRetryTransportStopsOnContextCancellation(t *testing.T) {
+	stub := &stubTransport{responses: []*http.Response{
+		newResponse(http.StatusServiceUnavailable, "", nil),
+	}}
+	rt := NewRetryTransport(stub, 5)
+	rt.BaseDelay = time.Minute // long enough that the context wins the race
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequest(http.MethodGet, "https://galaxy.example.com/api/v1/roles/", nil)
+	req = req.WithContext(ctx)
+	cancel()
+
+	_, err := rt.RoundTrip(req)
+	if err == nil {
+		t.Fatal("expected an error once the context is canceled")
+	}
+}
+
+func TestRetryDelayHonorsRetryAfterSeconds(t *testing.T) {
+	resp := newResponse(http.StatusTooManyRequests, "", map[string]string{"Retry-After": "2"})
+	got := retryDelay(resp, 0, time.Second)
+	if got != 2*time.Second {
+		t.Fatalf("expected Retry-After to be honored exactly, got %v", got)
+	}
+}
+
+func TestRetryDelayBacksOffExponentially(t *testing.T) {
+	resp := newResponse(http.StatusServiceUnavailable, "", nil)
+	d0 := retryDelay(resp, 0, 100*time.Millisecond)
+	d1 := retryDelay(resp, 1, 100*time.Millisecond)
+	if d0 < 100*time.Millisecond {
+		t.Fatalf("attempt 0 delay too small: %v", d0)
+	}
+	if d1 < 200*time.Millisecond {
+		t.Fatalf("attempt 1 delay should be roughly double attempt 0, got %v vs %v", d1, d0)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	cases := []struct {
+		status int
+		want   bool
+	}{
+		{http.StatusOK, false},
+		{http.StatusNotFound, false},
+		{http.StatusTooManyRequests, true},
+		{http.StatusInternalServerError, true},
+		{http.StatusBadGateway, true},
+	}
+	for _, c := range cases {
+		if got := isRetryable(newResponse(c.status, "", nil)); got != c.want {
+			t.Errorf("isRetryable(%d) = %v, want %v", c.status, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// sortVersions sorts versions in ascending order. Versions are compared
+// numerically component by component (e.g. "1.10.0" sorts after
+// "1.9.0"); a component that doesn't parse as a number is compared
+// lexically with the component at the same position.
+func sortVersions(versions []string) {
+	sort.Slice(versions, func(i, j int) bool {
+		return versionLess(versions[i], versions[j])
+	})
+}
+
+func versionLess(a, b string) bool {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aerr := strconv.Atoi(as[i])
+		bn, berr := strconv.Atoi(bs[i])
+		if aerr != nil || berr != nil {
+			if as[i] != bs[i] {
+				return as[i] < bs[i]
+			}
+			continue
+		}
+		if an != bn {
+			return an < bn
+		}
+	}
+	return len(as) < len(bs)
+}
@@ -0,0 +1,62 @@
+// Package requirements provides parsing for Ansible's requirements.yml
+// files, which declare the Roles and Collections a project depends on.
+package requirements
+
+import (
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Role is a single entry under the requirements.yml `roles:` key.
+type Role struct {
+	Name    string `yaml:"name"`
+	Source  string `yaml:"src"`
+	SCM     string `yaml:"scm"`
+	Version string `yaml:"version"`
+}
+
+// Collection is a single entry under the requirements.yml `collections:`
+// key. Name is expected to be in `namespace.collection` form unless
+// Source points at an alternate index or an SCM repository.
+type Collection struct {
+	Name    string `yaml:"name"`
+	Source  string `yaml:"source"`
+	Version string `yaml:"version"`
+}
+
+// Requirements is the parsed content of a requirements.yml file.
+type Requirements struct {
+	Roles       []Role       `yaml:"roles"`
+	Collections []Collection `yaml:"collections"`
+}
+
+// Parse reads a requirements.yml document from r and returns its
+// Roles and Collections. Both the legacy format (a bare list of Roles)
+// and the current format (a mapping with `roles:` and `collections:`
+// keys) are supported.
+func Parse(r io.Reader) (Requirements, error) {
+	var raw yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&raw); err != nil {
+		if err == io.EOF {
+			return Requirements{}, nil
+		}
+		return Requirements{}, err
+	}
+
+	if len(raw.Content) > 0 && raw.Content[0].Kind == yaml.SequenceNode {
+		var roles []Role
+		if err := raw.Content[0].Decode(&roles); err != nil {
+			return Requirements{}, err
+		}
+		return Requirements{Roles: roles}, nil
+	}
+
+	var reqs Requirements
+	if len(raw.Content) > 0 {
+		if err := raw.Content[0].Decode(&reqs); err != nil {
+			return Requirements{}, err
+		}
+	}
+	return reqs, nil
+}
@@ -0,0 +1,94 @@
+package requirements
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLegacyBareList(t *testing.T) {
+	doc := `
+- name: geerlingguy.docker
+  version: "4.0.0"
+- src: https://github.com/example/role.git
+  scm: git
+`
+	reqs, err := Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(reqs.Roles) != 2 {
+		t.Fatalf("expected 2 roles, got %d", len(reqs.Roles))
+	}
+	if reqs.Roles[0].Name != "geerlingguy.docker" || reqs.Roles[0].Version != "4.0.0" {
+		t.Fatalf("unexpected first role: %+v", reqs.Roles[0])
+	}
+	if reqs.Roles[1].Source != "https://github.com/example/role.git" || reqs.Roles[1].SCM != "git" {
+		t.Fatalf("unexpected second role: %+v", reqs.Roles[1])
+	}
+	if len(reqs.Collections) != 0 {
+		t.Fatalf("expected no collections from the legacy format, got %v", reqs.Collections)
+	}
+}
+
+func TestParseRolesAndCollections(t *testing.T) {
+	doc := `
+roles:
+  - name: geerlingguy.docker
+    version: "4.0.0"
+collections:
+  - name: community.general
+    version: "7.0.0"
+  - name: ns.internal
+    source: https://internal.example.com/git/collection.git
+`
+	reqs, err := Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(reqs.Roles) != 1 || reqs.Roles[0].Name != "geerlingguy.docker" {
+		t.Fatalf("unexpected roles: %+v", reqs.Roles)
+	}
+	if len(reqs.Collections) != 2 {
+		t.Fatalf("expected 2 collections, got %d", len(reqs.Collections))
+	}
+	if reqs.Collections[0].Name != "community.general" || reqs.Collections[0].Version != "7.0.0" {
+		t.Fatalf("unexpected first collection: %+v", reqs.Collections[0])
+	}
+	if reqs.Collections[1].Source != "https://internal.example.com/git/collection.git" {
+		t.Fatalf("unexpected second collection: %+v", reqs.Collections[1])
+	}
+}
+
+func TestParseCollectionsOnly(t *testing.T) {
+	doc := `
+collections:
+  - name: community.general
+`
+	reqs, err := Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(reqs.Roles) != 0 {
+		t.Fatalf("expected no roles, got %v", reqs.Roles)
+	}
+	if len(reqs.Collections) != 1 || reqs.Collections[0].Name != "community.general" {
+		t.Fatalf("unexpected collections: %v", reqs.Collections)
+	}
+}
+
+func TestParseEmptyDocument(t *testing.T) {
+	reqs, err := Parse(strings.NewReader(""))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(reqs.Roles) != 0 || len(reqs.Collections) != 0 {
+		t.Fatalf("expected an empty Requirements, got %+v", reqs)
+	}
+}
+
+func TestParseInvalidYAML(t *testing.T) {
+	_, err := Parse(strings.NewReader("roles: [this is not, valid"))
+	if err == nil {
+		t.Fatal("expected an error for malformed YAML")
+	}
+}